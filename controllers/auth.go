@@ -4,13 +4,14 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"net/smtp"
 	"os"
 	"strconv"
 	"time"
 
 	_ "github.com/alastor-4/sylcot-go-gin-backend/docs"
+	"github.com/alastor-4/sylcot-go-gin-backend/email"
 	"github.com/alastor-4/sylcot-go-gin-backend/models"
+	"github.com/alastor-4/sylcot-go-gin-backend/pkg/apierror"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
@@ -30,38 +31,32 @@ type AuthController struct {
 // @Produce json
 // @Param user body models.User true "Registration data"
 // @Success 201 {object} map[string]interface{} "message: User registered successfully..."
-// @Failure 400 {object} map[string]interface{} "error: Validation failed, details: field errors"
-// @Failure 409 {object} map[string]interface{} "error: User already exists"
-// @Failure 500 {object} map[string]interface{} "error: Internal server error"
+// @Failure 400 {object} apierror.Error "code: VALIDATION_FAILED, details: field errors"
+// @Failure 409 {object} apierror.Error "code: USER_ALREADY_EXISTS"
+// @Failure 500 {object} apierror.Error "code: INTERNAL_ERROR"
 // @Router /auth/register [post]
 func (ac *AuthController) Register(c *gin.Context) {
 	var user models.User
 	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": map[string]interface{}{},
-		})
+		apierror.Respond(c, apierror.Wrap(apierror.InvalidRequest, "Invalid request data", err))
 		return
 	}
 
 	if err := user.Validate(); err != nil {
 		validationErrors := models.GetValidationMessages(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Validation failed",
-			"details": validationErrors,
-		})
+		apierror.Respond(c, apierror.Wrap(apierror.ValidationFailed, "Validation failed", err).WithDetails(validationErrors))
 		return
 	}
 
 	var existingUser models.User
 	if err := ac.DB.Where("email = ?", user.Email).First(&existingUser).Error; err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "User with that email already registered"})
+		apierror.Respond(c, apierror.New(apierror.UserAlreadyExists, "User with that email already registered"))
 		return
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error encrypting password"})
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Error encrypting password", err))
 		return
 	}
 
@@ -72,18 +67,16 @@ func (ac *AuthController) Register(c *gin.Context) {
 		Email:      user.Email,
 		Password:   string(hashedPassword),
 		IsVerified: false,
-		Token:      verificationToken,
+		Token:      hashToken(verificationToken),
 	}
 
 	if err := ac.DB.Create(&newUser).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not register the user"})
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not register the user", err))
 		return
 	}
 
 	verificationLink := "http://localhost:8080/auth/verify-email?token=" + verificationToken
-	if err := sendVerificationEmail(user.Email, verificationLink); err != nil {
-		log.Printf("Could not send verification email to %s: %v", user.Email, err)
-	}
+	sendVerificationEmail(ac.DB, user.Name, user.Email, verificationLink)
 
 	fmt.Println(verificationToken)
 
@@ -102,11 +95,12 @@ type LoginRequest struct {
 // @Accept json
 // @Produce json
 // @Param credentials body LoginRequest true "Login credentials"
-// @Success 200 {object} map[string]interface{} "token: JWT string"
-// @Failure 400 {object} map[string]interface{} "error: Invalid data"
-// @Failure 401 {object} map[string]interface{} "error: Invalid credentials"
-// @Failure 403 {object} map[string]interface{} "error: Email not verified"
-// @Failure 500 {object} map[string]interface{} "error: Internal server error"
+// @Success 200 {object} map[string]interface{} "token, refresh_token, or mfa_required+mfa_token if 2FA is enabled"
+// @Failure 400 {object} apierror.Error "code: INVALID_REQUEST"
+// @Failure 401 {object} apierror.Error "code: INVALID_CREDENTIALS"
+// @Failure 403 {object} apierror.Error "code: EMAIL_NOT_VERIFIED"
+// @Failure 429 {object} apierror.Error "code: TOO_MANY_ATTEMPTS"
+// @Failure 500 {object} apierror.Error "code: INTERNAL_ERROR"
 func (ac *AuthController) Login(c *gin.Context) {
 	var loginData struct {
 		Email    string `json:"email"`
@@ -114,33 +108,81 @@ func (ac *AuthController) Login(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&loginData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		apierror.Respond(c, apierror.Wrap(apierror.InvalidRequest, "Invalid data", err))
+		return
+	}
+
+	if _, locked := checkLockout(ac.DB, loginData.Email); locked {
+		apierror.Respond(c, apierror.New(apierror.TooManyAttempts, "Too many failed attempts, please try again later"))
 		return
 	}
 
 	var user models.User
 	if err := ac.DB.Where("email = ?", loginData.Email).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		apierror.Respond(c, apierror.New(apierror.InvalidCredentials, "Invalid email or password"))
 		return
 	}
 
 	if !user.IsVerified {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email first"})
+		apierror.Respond(c, apierror.New(apierror.EmailNotVerified, "Please verify your email first"))
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginData.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		recordFailedLogin(ac.DB, user.Name, user.Email)
+		apierror.Respond(c, apierror.New(apierror.InvalidCredentials, "Invalid email or password"))
+		return
+	}
+
+	resetFailedLogins(ac.DB, user.Email)
+
+	var totpSecret models.TOTPSecret
+	if err := ac.DB.Where("user_id = ? AND confirmed = ?", user.ID, true).First(&totpSecret).Error; err == nil {
+		mfaToken, err := generateMFAToken(user.ID)
+		if err != nil {
+			apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not generate mfa token", err))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"mfa_required": true, "mfa_token": mfaToken})
 		return
 	}
 
 	jwtToken, err := GenerateJWT(user.Email, int(user.ID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate JWT Token"})
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not generate JWT Token", err))
+		return
+	}
+
+	refreshToken, err := issueRefreshToken(ac.DB, user.ID, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not issue refresh token", err))
+		return
+	}
+
+	sendLoginNotificationEmail(ac.DB, user.Name, user.Email, c.ClientIP())
+
+	c.JSON(http.StatusOK, gin.H{"token": jwtToken, "refresh_token": refreshToken})
+}
+
+// sendLoginNotificationEmail renders the login-notification template and
+// hands it to the queued email worker pool.
+func sendLoginNotificationEmail(db *gorm.DB, name, to, ip string) {
+	htmlBody, textBody, err := email.Render(email.TemplateLoginNotification, struct {
+		Name string
+		IP   string
+		Time string
+	}{Name: name, IP: ip, Time: time.Now().Format(time.RFC1123)})
+	if err != nil {
+		log.Printf("Could not render login notification email for %s: %v", to, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": jwtToken})
+	email.Enqueue(db, email.TemplateLoginNotification, email.Message{
+		To:       to,
+		Subject:  "New login to your account",
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	})
 }
 
 // VerifyEmail godoc
@@ -150,19 +192,19 @@ func (ac *AuthController) Login(c *gin.Context) {
 // @Produce json
 // @Param token query string true "Verification token"
 // @Success 200 {object} map[string]interface{} "message: Verification success message"
-// @Failure 400 {object} map[string]interface{} "error: Token required"
-// @Failure 404 {object} map[string]interface{} "error: Invalid token"
-// @Failure 500 {object} map[string]interface{} "error: Internal server error"
+// @Failure 400 {object} apierror.Error "code: INVALID_REQUEST"
+// @Failure 404 {object} apierror.Error "code: TOKEN_INVALID"
+// @Failure 500 {object} apierror.Error "code: INTERNAL_ERROR"
 // @Router /auth/verify-email [get]
 func (ac *AuthController) VerifyEmail(c *gin.Context) {
 	token := c.Query("token")
 	if token == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Token required"})
+		apierror.Respond(c, apierror.New(apierror.InvalidRequest, "Token required"))
 		return
 	}
 	var user models.User
-	if err := ac.DB.Where("token = ?", token).First(&user).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Invalid token"})
+	if err := ac.DB.Where("token = ?", hashToken(token)).First(&user).Error; err != nil {
+		apierror.Respond(c, apierror.New(apierror.TokenInvalid, "Invalid token"))
 		return
 	}
 
@@ -170,13 +212,13 @@ func (ac *AuthController) VerifyEmail(c *gin.Context) {
 	user.Token = ""
 
 	if err := ac.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating the user"})
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Error updating the user", err))
 		return
 	}
 
 	_, err := GenerateJWT(user.Email, int(user.ID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating JWT Token"})
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Error generating JWT Token", err))
 		return
 	}
 
@@ -185,40 +227,57 @@ func (ac *AuthController) VerifyEmail(c *gin.Context) {
 	})
 }
 
+// Access JWTs are now short-lived by default: sessions are kept alive via
+// the refresh token issued alongside them instead of a long-lived JWT.
 func getJWTExpiration() time.Duration {
 	minutesStr := os.Getenv("JWT_EXPIRATION_MINUTES")
 	if minutesStr == "" {
-		return time.Minute * 4320
+		return time.Minute * 15
 	}
 	minutes, err := strconv.Atoi(minutesStr)
 	if err != nil {
-		return time.Minute * 4320
+		return time.Minute * 15
 	}
 	return time.Minute * time.Duration(minutes)
 }
 
+// jwtPurposeAccess marks a full access JWT, as opposed to the narrowly
+// scoped mfa_pending token issued between the password and TOTP steps of
+// login (see generateMFAToken in two_factor.go). AuthRequired rejects any
+// token whose purpose isn't this one, so a password-only mfa_pending token
+// can never be used to reach a protected route.
+const jwtPurposeAccess = "access"
+
 func GenerateJWT(email string, id int) (string, error) {
 	secret := os.Getenv("JWT_SECRET")
 	expiration := getJWTExpiration()
 	claims := jwt.MapClaims{
-		"email":  email,
-		"userId": id,
-		"iat":    time.Now().Unix(),
-		"exp":    time.Now().Add(expiration).Unix(),
+		"email":   email,
+		"userId":  id,
+		"purpose": jwtPurposeAccess,
+		"iat":     time.Now().Unix(),
+		"exp":     time.Now().Add(expiration).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))
 }
 
-func sendVerificationEmail(email, link string) error {
-	smtpHost := os.Getenv("SMTP_HOST")
-	smtpPort := os.Getenv("SMTP_PORT")
-	from := os.Getenv("SMTP_USER")
-	password := os.Getenv("SMTP_PASSWORD")
-	subject := "Email Verification"
-	body := "Click the following link to verify your email: " + link
-
-	auth := smtp.PlainAuth("", from, password, smtpHost)
-	msg := []byte("Subject: " + subject + "\r\n\r\n" + body)
-	return smtp.SendMail(smtpHost+":"+smtpPort, auth, from, []string{email}, msg)
+// sendVerificationEmail renders the verification template and hands it to
+// the queued email worker pool, so Register never blocks on mail delivery.
+func sendVerificationEmail(db *gorm.DB, name, to, link string) {
+	htmlBody, textBody, err := email.Render(email.TemplateVerification, struct {
+		Name string
+		Link string
+	}{Name: name, Link: link})
+	if err != nil {
+		log.Printf("Could not render verification email for %s: %v", to, err)
+		return
+	}
+
+	email.Enqueue(db, email.TemplateVerification, email.Message{
+		To:       to,
+		Subject:  "Verify your email",
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	})
 }
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"log"
+	"time"
+
+	"github.com/alastor-4/sylcot-go-gin-backend/email"
+	"github.com/alastor-4/sylcot-go-gin-backend/models"
+	"gorm.io/gorm"
+)
+
+const (
+	lockoutThreshold    = 5
+	lockoutBaseCooldown = time.Minute
+	lockoutMaxCooldown  = 24 * time.Hour
+)
+
+// checkLockout returns the existing FailedLogin row (if any) and whether
+// the account is currently locked out.
+func checkLockout(db *gorm.DB, email string) (*models.FailedLogin, bool) {
+	var failedLogin models.FailedLogin
+	if err := db.Where("email = ?", email).First(&failedLogin).Error; err != nil {
+		return nil, false
+	}
+	return &failedLogin, failedLogin.IsLocked()
+}
+
+// recordFailedLogin bumps the failure counter for email and, once it
+// reaches lockoutThreshold, locks the account for an exponentially-growing
+// cooldown, notifying the user by email.
+func recordFailedLogin(db *gorm.DB, name, userEmail string) {
+	var failedLogin models.FailedLogin
+	if err := db.Where("email = ?", userEmail).First(&failedLogin).Error; err != nil {
+		failedLogin = models.FailedLogin{Email: userEmail}
+	}
+
+	failedLogin.Attempts++
+
+	if failedLogin.Attempts >= lockoutThreshold {
+		cooldown := lockoutBaseCooldown << uint(failedLogin.Attempts-lockoutThreshold)
+		if cooldown > lockoutMaxCooldown || cooldown <= 0 {
+			cooldown = lockoutMaxCooldown
+		}
+		lockedUntil := time.Now().Add(cooldown)
+		failedLogin.LockedUntil = &lockedUntil
+		sendAccountLockedEmail(db, name, userEmail, lockedUntil)
+	}
+
+	db.Save(&failedLogin)
+}
+
+// resetFailedLogins clears the failure counter after a successful login.
+func resetFailedLogins(db *gorm.DB, userEmail string) {
+	db.Where("email = ?", userEmail).Delete(&models.FailedLogin{})
+}
+
+func sendAccountLockedEmail(db *gorm.DB, name, to string, lockedUntil time.Time) {
+	htmlBody, textBody, err := email.Render(email.TemplateAccountLocked, struct {
+		Name        string
+		LockedUntil string
+	}{Name: name, LockedUntil: lockedUntil.Format(time.RFC1123)})
+	if err != nil {
+		log.Printf("Could not render account-locked email for %s: %v", to, err)
+		return
+	}
+
+	email.Enqueue(db, email.TemplateAccountLocked, email.Message{
+		To:       to,
+		Subject:  "Your account has been temporarily locked",
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	})
+}
@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/alastor-4/sylcot-go-gin-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/google"
+	"gorm.io/gorm"
+)
+
+type OAuthController struct {
+	DB *gorm.DB
+}
+
+// oauthUserInfo is the subset of a provider's userinfo response we care
+// about, normalized across Google/GitHub/GitLab.
+type oauthUserInfo struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+var gitlabEndpoint = oauth2.Endpoint{
+	AuthURL:  "https://gitlab.com/oauth/authorize",
+	TokenURL: "https://gitlab.com/oauth/token",
+}
+
+func oauthConfig(provider string) *oauth2.Config {
+	prefix := provider + "_OAUTH_"
+	clientID := os.Getenv(prefix + "CLIENT_ID")
+	clientSecret := os.Getenv(prefix + "CLIENT_SECRET")
+	redirectURL := os.Getenv(prefix + "REDIRECT_URL")
+
+	cfg := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+	}
+
+	switch provider {
+	case "google":
+		cfg.Endpoint = google.Endpoint
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	case "github":
+		cfg.Endpoint = github.Endpoint
+		cfg.Scopes = []string{"read:user", "user:email"}
+	case "gitlab":
+		cfg.Endpoint = gitlabEndpoint
+		cfg.Scopes = []string{"read_user"}
+	default:
+		return nil
+	}
+
+	return cfg
+}
+
+func fetchOAuthUserInfo(provider, accessToken string) (*oauthUserInfo, error) {
+	switch provider {
+	case "google":
+		return fetchGoogleUserInfo(accessToken)
+	case "github":
+		return fetchGitHubUserInfo(accessToken)
+	case "gitlab":
+		return fetchGitLabUserInfo(accessToken)
+	default:
+		return nil, gorm.ErrInvalidData
+	}
+}
+
+// getJSON issues an authenticated GET request against url and decodes the
+// JSON response body into out.
+func getJSON(url, accessToken string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func fetchGoogleUserInfo(accessToken string) (*oauthUserInfo, error) {
+	var raw struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if err := getJSON("https://www.googleapis.com/oauth2/v3/userinfo", accessToken, &raw); err != nil {
+		return nil, err
+	}
+
+	return &oauthUserInfo{
+		Subject:       raw.Sub,
+		Email:         raw.Email,
+		EmailVerified: raw.EmailVerified,
+	}, nil
+}
+
+// fetchGitHubUserInfo uses the numeric, per-account GitHub user id as the
+// subject (never derivable from the provider name alone) and looks up the
+// primary verified address via /user/emails, since /user's "email" field is
+// null whenever the user has made their primary email private.
+func fetchGitHubUserInfo(accessToken string) (*oauthUserInfo, error) {
+	var user struct {
+		ID int64 `json:"id"`
+	}
+	if err := getJSON("https://api.github.com/user", accessToken, &user); err != nil {
+		return nil, err
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON("https://api.github.com/user/emails", accessToken, &emails); err != nil {
+		return nil, err
+	}
+
+	info := &oauthUserInfo{Subject: strconv.FormatInt(user.ID, 10)}
+	for _, e := range emails {
+		if e.Primary {
+			info.Email = e.Email
+			info.EmailVerified = e.Verified
+			break
+		}
+	}
+
+	return info, nil
+}
+
+// fetchGitLabUserInfo uses the numeric, per-account GitLab user id as the
+// subject and treats the email as verified only if GitLab has confirmed it
+// (confirmed_at is non-null).
+func fetchGitLabUserInfo(accessToken string) (*oauthUserInfo, error) {
+	var raw struct {
+		ID          int64   `json:"id"`
+		Email       string  `json:"email"`
+		ConfirmedAt *string `json:"confirmed_at"`
+	}
+	if err := getJSON("https://gitlab.com/api/v4/user", accessToken, &raw); err != nil {
+		return nil, err
+	}
+
+	return &oauthUserInfo{
+		Subject:       strconv.FormatInt(raw.ID, 10),
+		Email:         raw.Email,
+		EmailVerified: raw.ConfirmedAt != nil,
+	}, nil
+}
+
+// OAuthLogin godoc
+// @Summary Start OAuth2 login
+// @Description Redirect the user to the provider's consent screen
+// @Tags authentication
+// @Param provider path string true "Provider name (google, github, gitlab)"
+// @Success 307 {string} string "redirect to provider"
+// @Failure 400 {object} map[string]interface{} "error: Unsupported provider"
+// @Router /auth/oauth/{provider}/login [get]
+func (oc *OAuthController) OAuthLogin(c *gin.Context) {
+	provider := c.Param("provider")
+	cfg := oauthConfig(provider)
+	if cfg == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported provider"})
+		return
+	}
+
+	state := uuid.NewString()
+	c.SetCookie("oauth_state", state, 600, "/", "", false, true)
+	c.Redirect(http.StatusTemporaryRedirect, cfg.AuthCodeURL(state))
+}
+
+// OAuthCallback godoc
+// @Summary OAuth2 provider callback
+// @Description Exchange the authorization code, link or create the account, and return a JWT
+// @Tags authentication
+// @Param provider path string true "Provider name (google, github, gitlab)"
+// @Param code query string true "Authorization code"
+// @Param state query string true "CSRF state"
+// @Success 200 {object} map[string]interface{} "token: JWT string"
+// @Failure 400 {object} map[string]interface{} "error: Unsupported provider or invalid state"
+// @Failure 403 {object} map[string]interface{} "error: SSO-only mode, account does not exist"
+// @Failure 500 {object} map[string]interface{} "error: Internal server error"
+// @Router /auth/oauth/{provider}/callback [get]
+func (oc *OAuthController) OAuthCallback(c *gin.Context) {
+	provider := c.Param("provider")
+	cfg := oauthConfig(provider)
+	if cfg == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported provider"})
+		return
+	}
+
+	state, _ := c.Cookie("oauth_state")
+	if state == "" || state != c.Query("state") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OAuth state"})
+		return
+	}
+
+	code := c.Query("code")
+	token, err := cfg.Exchange(c.Request.Context(), code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not exchange authorization code"})
+		return
+	}
+
+	info, err := fetchOAuthUserInfo(provider, token.AccessToken)
+	if err != nil || info.Email == "" {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch provider user info"})
+		return
+	}
+
+	var identity models.OAuthIdentity
+	identityErr := oc.DB.Where("provider = ? AND subject = ?", provider, info.Subject).First(&identity).Error
+
+	var user models.User
+	if identityErr == nil {
+		if err := oc.DB.First(&user, identity.UserID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Linked user not found"})
+			return
+		}
+	} else {
+		// Only a verified provider email may be trusted to link to (or
+		// create) a local account: an unverified email could belong to
+		// someone other than the account holder at the provider.
+		if !info.EmailVerified {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Provider email is not verified"})
+			return
+		}
+
+		if err := oc.DB.Where("email = ?", info.Email).First(&user).Error; err != nil {
+			if os.Getenv("SSO_ONLY_MODE") == "true" {
+				c.JSON(http.StatusForbidden, gin.H{"error": "SSO-only mode: account does not exist"})
+				return
+			}
+
+			user = models.User{
+				Name:       info.Email,
+				Email:      info.Email,
+				IsVerified: true,
+			}
+			if err := oc.DB.Create(&user).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create the user"})
+				return
+			}
+		}
+
+		identity = models.OAuthIdentity{
+			UserID:   user.ID,
+			Provider: provider,
+			Subject:  info.Subject,
+			Email:    info.Email,
+		}
+	}
+
+	identity.AccessToken = token.AccessToken
+	identity.RefreshToken = token.RefreshToken
+	if err := oc.DB.Save(&identity).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not persist the OAuth identity"})
+		return
+	}
+
+	jwtToken, err := GenerateJWT(user.Email, int(user.ID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate JWT Token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": jwtToken})
+}
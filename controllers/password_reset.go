@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/alastor-4/sylcot-go-gin-backend/email"
+	"github.com/alastor-4/sylcot-go-gin-backend/models"
+	"github.com/alastor-4/sylcot-go-gin-backend/pkg/apierror"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const passwordResetTokenTTL = 30 * time.Minute
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" example:"user@example.com"`
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset link
+// @Description Always returns 200 to avoid revealing whether the email is registered
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param body body ForgotPasswordRequest true "Account email"
+// @Success 200 {object} map[string]interface{} "message: If that email exists, a reset link has been sent"
+// @Router /auth/forgot-password [post]
+func (ac *AuthController) ForgotPassword(c *gin.Context) {
+	var body ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InvalidRequest, "Invalid request data", err))
+		return
+	}
+
+	const genericMessage = "If that email exists, a reset link has been sent"
+
+	var user models.User
+	if err := ac.DB.Where("email = ?", body.Email).First(&user).Error; err != nil {
+		c.JSON(http.StatusOK, gin.H{"message": genericMessage})
+		return
+	}
+
+	plaintext := uuid.NewString()
+	resetToken := models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(plaintext),
+		ExpiresAt: time.Now().Add(passwordResetTokenTTL),
+	}
+	if err := ac.DB.Create(&resetToken).Error; err != nil {
+		c.JSON(http.StatusOK, gin.H{"message": genericMessage})
+		return
+	}
+
+	resetLink := "http://localhost:8080/auth/reset-password?token=" + plaintext
+	sendPasswordResetEmail(ac.DB, user.Name, user.Email, resetLink)
+
+	c.JSON(http.StatusOK, gin.H{"message": genericMessage})
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// ResetPassword godoc
+// @Summary Reset a password using a forgot-password token
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param body body ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} map[string]interface{} "message: Password reset successfully"
+// @Failure 400 {object} apierror.Error "code: INVALID_REQUEST"
+// @Failure 401 {object} apierror.Error "code: TOKEN_EXPIRED"
+// @Failure 500 {object} apierror.Error "code: INTERNAL_ERROR"
+// @Router /auth/reset-password [post]
+func (ac *AuthController) ResetPassword(c *gin.Context) {
+	var body ResetPasswordRequest
+	if err := c.ShouldBindJSON(&body); err != nil || body.Token == "" || body.NewPassword == "" {
+		apierror.Respond(c, apierror.New(apierror.InvalidRequest, "Token and new password are required"))
+		return
+	}
+
+	var resetToken models.PasswordResetToken
+	if err := ac.DB.Where("token_hash = ?", hashToken(body.Token)).First(&resetToken).Error; err != nil || !resetToken.IsActive() {
+		apierror.Respond(c, apierror.New(apierror.TokenExpired, "Invalid or expired reset token"))
+		return
+	}
+
+	var user models.User
+	if err := ac.DB.First(&user, resetToken.UserID).Error; err != nil {
+		apierror.Respond(c, apierror.New(apierror.TokenExpired, "Invalid or expired reset token"))
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(body.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Error encrypting password", err))
+		return
+	}
+	user.Password = string(hashedPassword)
+	if err := ac.DB.Save(&user).Error; err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not update the password", err))
+		return
+	}
+
+	now := time.Now()
+	resetToken.UsedAt = &now
+	ac.DB.Save(&resetToken)
+
+	ac.DB.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", user.ID).
+		Update("revoked_at", now)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// ChangePassword godoc
+// @Summary Change the authenticated user's password
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body ChangePasswordRequest true "Old and new password"
+// @Success 200 {object} map[string]interface{} "message: Password changed successfully"
+// @Failure 400 {object} apierror.Error "code: INVALID_REQUEST"
+// @Failure 401 {object} apierror.Error "code: INVALID_CREDENTIALS"
+// @Failure 500 {object} apierror.Error "code: INTERNAL_ERROR"
+// @Router /auth/change-password [post]
+func (ac *AuthController) ChangePassword(c *gin.Context) {
+	var body ChangePasswordRequest
+	if err := c.ShouldBindJSON(&body); err != nil || body.OldPassword == "" || body.NewPassword == "" {
+		apierror.Respond(c, apierror.New(apierror.InvalidRequest, "Old and new password are required"))
+		return
+	}
+
+	userID := c.GetUint("userId")
+	var user models.User
+	if err := ac.DB.First(&user, userID).Error; err != nil {
+		apierror.Respond(c, apierror.New(apierror.InvalidCredentials, "User not found"))
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(body.OldPassword)); err != nil {
+		apierror.Respond(c, apierror.New(apierror.InvalidCredentials, "Old password is incorrect"))
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(body.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Error encrypting password", err))
+		return
+	}
+	user.Password = string(hashedPassword)
+	if err := ac.DB.Save(&user).Error; err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not update the password", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
+
+// sendPasswordResetEmail renders the password-reset template and hands it
+// to the queued email worker pool.
+func sendPasswordResetEmail(db *gorm.DB, name, to, link string) {
+	htmlBody, textBody, err := email.Render(email.TemplatePasswordReset, struct {
+		Name string
+		Link string
+	}{Name: name, Link: link})
+	if err != nil {
+		log.Printf("Could not render password reset email for %s: %v", to, err)
+		return
+	}
+
+	email.Enqueue(db, email.TemplatePasswordReset, email.Message{
+		To:       to,
+		Subject:  "Reset your password",
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	})
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/alastor-4/sylcot-go-gin-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const refreshTokenTTL = time.Hour * 24 * 30
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueRefreshToken creates and persists a new opaque refresh token for the
+// user, returning the plaintext token to hand back to the client.
+func issueRefreshToken(db *gorm.DB, userID uint, userAgent, ip string) (string, error) {
+	plaintext := uuid.NewString()
+	jti := uuid.NewString()
+
+	refreshToken := models.RefreshToken{
+		UserID:    userID,
+		JTI:       jti,
+		TokenHash: hashToken(plaintext),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+		UserAgent: userAgent,
+		IP:        ip,
+	}
+	if err := db.Create(&refreshToken).Error; err != nil {
+		return "", err
+	}
+
+	return plaintext, nil
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh godoc
+// @Summary Rotate the access/refresh token pair
+// @Description Exchange a valid refresh token for a new JWT and a new refresh token
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param body body RefreshRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{} "token, refresh_token"
+// @Failure 400 {object} map[string]interface{} "error: Refresh token required"
+// @Failure 401 {object} map[string]interface{} "error: Invalid or expired refresh token"
+// @Failure 500 {object} map[string]interface{} "error: Internal server error"
+// @Router /auth/refresh [post]
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var body RefreshRequest
+	if err := c.ShouldBindJSON(&body); err != nil || body.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token required"})
+		return
+	}
+
+	presentedHash := hashToken(body.RefreshToken)
+
+	var stored models.RefreshToken
+	if err := ac.DB.Where("token_hash = ?", presentedHash).First(&stored).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	if stored.RevokedAt != nil {
+		// A revoked token being presented again means it (or a descendant of
+		// it) was stolen: kill the whole chain for this user.
+		ac.DB.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", stored.UserID).
+			Update("revoked_at", time.Now())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	if !stored.IsActive() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	var user models.User
+	if err := ac.DB.First(&user, stored.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	newRefreshToken, err := issueRefreshToken(ac.DB, user.ID, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not issue refresh token"})
+		return
+	}
+
+	var rotated models.RefreshToken
+	ac.DB.Where("token_hash = ?", hashToken(newRefreshToken)).First(&rotated)
+
+	now := time.Now()
+	stored.RevokedAt = &now
+	stored.ReplacedBy = rotated.JTI
+	if err := ac.DB.Save(&stored).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not rotate refresh token"})
+		return
+	}
+
+	jwtToken, err := GenerateJWT(user.Email, int(user.ID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate JWT Token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": jwtToken, "refresh_token": newRefreshToken})
+}
+
+// Logout godoc
+// @Summary Revoke the presented refresh token
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param body body RefreshRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{} "message: Logged out"
+// @Failure 400 {object} map[string]interface{} "error: Refresh token required"
+// @Router /auth/logout [post]
+func (ac *AuthController) Logout(c *gin.Context) {
+	var body RefreshRequest
+	if err := c.ShouldBindJSON(&body); err != nil || body.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token required"})
+		return
+	}
+
+	now := time.Now()
+	ac.DB.Model(&models.RefreshToken{}).
+		Where("token_hash = ? AND revoked_at IS NULL", hashToken(body.RefreshToken)).
+		Update("revoked_at", now)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// LogoutAll godoc
+// @Summary Revoke every refresh token belonging to the authenticated user
+// @Tags authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "message: All sessions logged out"
+// @Router /auth/logout-all [post]
+func (ac *AuthController) LogoutAll(c *gin.Context) {
+	userID := c.GetUint("userId")
+
+	now := time.Now()
+	ac.DB.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now)
+
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions logged out"})
+}
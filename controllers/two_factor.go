@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/alastor-4/sylcot-go-gin-backend/models"
+	"github.com/alastor-4/sylcot-go-gin-backend/pkg/apierror"
+	cryptox "github.com/alastor-4/sylcot-go-gin-backend/pkg/crypto"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	mfaTokenTTL   = 5 * time.Minute
+	recoveryCodes = 8
+)
+
+func generateMFAToken(userID uint) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	claims := jwt.MapClaims{
+		"userId":  userID,
+		"purpose": "mfa_pending",
+		"iat":     time.Now().Unix(),
+		"exp":     time.Now().Add(mfaTokenTTL).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+func parseMFAToken(mfaToken string) (uint, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(mfaToken, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(os.Getenv("JWT_SECRET")), nil
+	})
+	if err != nil || !token.Valid {
+		return 0, jwt.NewValidationError("invalid mfa token", jwt.ValidationErrorSignatureInvalid)
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != "mfa_pending" {
+		return 0, jwt.NewValidationError("invalid mfa token purpose", jwt.ValidationErrorClaimsInvalid)
+	}
+	userID, _ := claims["userId"].(float64)
+	return uint(userID), nil
+}
+
+// SetupTwoFactor godoc
+// @Summary Begin TOTP enrollment
+// @Description Generates a pending TOTP secret and returns its provisioning URI and a QR code
+// @Tags two-factor
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "provisioning_uri, qr_code_png (base64)"
+// @Failure 500 {object} apierror.Error "code: INTERNAL_ERROR"
+// @Router /auth/2fa/setup [post]
+func (ac *AuthController) SetupTwoFactor(c *gin.Context) {
+	userID := c.GetUint("userId")
+
+	var user models.User
+	if err := ac.DB.First(&user, userID).Error; err != nil {
+		apierror.Respond(c, apierror.New(apierror.InvalidCredentials, "User not found"))
+		return
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      "Sylcot",
+		AccountName: user.Email,
+	})
+	if err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not generate TOTP secret", err))
+		return
+	}
+
+	encryptedSecret, err := cryptox.Encrypt(key.Secret())
+	if err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not encrypt TOTP secret", err))
+		return
+	}
+
+	ac.DB.Where("user_id = ?", userID).Delete(&models.TOTPSecret{})
+	totpSecret := models.TOTPSecret{UserID: userID, Secret: encryptedSecret, Confirmed: false}
+	if err := ac.DB.Create(&totpSecret).Error; err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not persist TOTP secret", err))
+		return
+	}
+
+	png, err := qrcode.Encode(key.String(), qrcode.Medium, 256)
+	if err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not render QR code", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"provisioning_uri": key.String(),
+		"qr_code_png":      png,
+	})
+}
+
+type VerifyTwoFactorRequest struct {
+	Code string `json:"code"`
+}
+
+// VerifyTwoFactorSetup godoc
+// @Summary Confirm TOTP enrollment
+// @Description Validates a code against the pending secret and enables 2FA, returning recovery codes
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body VerifyTwoFactorRequest true "6-digit TOTP code"
+// @Success 200 {object} map[string]interface{} "recovery_codes: []string"
+// @Failure 400 {object} apierror.Error "code: INVALID_REQUEST"
+// @Failure 401 {object} apierror.Error "code: INVALID_CREDENTIALS"
+// @Router /auth/2fa/verify [post]
+func (ac *AuthController) VerifyTwoFactorSetup(c *gin.Context) {
+	userID := c.GetUint("userId")
+
+	var body VerifyTwoFactorRequest
+	if err := c.ShouldBindJSON(&body); err != nil || body.Code == "" {
+		apierror.Respond(c, apierror.New(apierror.InvalidRequest, "Code is required"))
+		return
+	}
+
+	var totpSecret models.TOTPSecret
+	if err := ac.DB.Where("user_id = ?", userID).First(&totpSecret).Error; err != nil {
+		apierror.Respond(c, apierror.New(apierror.InvalidRequest, "2FA setup has not been started"))
+		return
+	}
+
+	secret, err := cryptox.Decrypt(totpSecret.Secret)
+	if err != nil || !totp.Validate(body.Code, secret) {
+		apierror.Respond(c, apierror.New(apierror.InvalidCredentials, "Invalid code"))
+		return
+	}
+
+	totpSecret.Confirmed = true
+	if err := ac.DB.Save(&totpSecret).Error; err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not confirm 2FA", err))
+		return
+	}
+
+	plainCodes := make([]string, 0, recoveryCodes)
+	for i := 0; i < recoveryCodes; i++ {
+		plain := uuid.NewString()
+		hash, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+		if err != nil {
+			continue
+		}
+		ac.DB.Create(&models.RecoveryCode{UserID: userID, Hash: string(hash)})
+		plainCodes = append(plainCodes, plain)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": plainCodes})
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable TOTP 2FA for the authenticated user
+// @Tags two-factor
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "message: Two-factor authentication disabled"
+// @Router /auth/2fa/disable [post]
+func (ac *AuthController) DisableTwoFactor(c *gin.Context) {
+	userID := c.GetUint("userId")
+
+	ac.DB.Where("user_id = ?", userID).Delete(&models.TOTPSecret{})
+	ac.DB.Where("user_id = ?", userID).Delete(&models.RecoveryCode{})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+type TwoFactorLoginRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+// TwoFactorLogin godoc
+// @Summary Complete login by exchanging an mfa_token and TOTP/recovery code for a JWT
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param body body TwoFactorLoginRequest true "mfa_token and 6-digit code (or recovery code)"
+// @Success 200 {object} map[string]interface{} "token, refresh_token"
+// @Failure 401 {object} apierror.Error "code: INVALID_CREDENTIALS"
+// @Failure 500 {object} apierror.Error "code: INTERNAL_ERROR"
+// @Router /auth/2fa/login [post]
+func (ac *AuthController) TwoFactorLogin(c *gin.Context) {
+	var body TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&body); err != nil || body.MFAToken == "" || body.Code == "" {
+		apierror.Respond(c, apierror.New(apierror.InvalidRequest, "mfa_token and code are required"))
+		return
+	}
+
+	userID, err := parseMFAToken(body.MFAToken)
+	if err != nil {
+		apierror.Respond(c, apierror.New(apierror.TokenInvalid, "Invalid or expired mfa token"))
+		return
+	}
+
+	var totpSecret models.TOTPSecret
+	if err := ac.DB.Where("user_id = ? AND confirmed = ?", userID, true).First(&totpSecret).Error; err != nil {
+		apierror.Respond(c, apierror.New(apierror.InvalidCredentials, "2FA is not enabled for this account"))
+		return
+	}
+
+	secret, err := cryptox.Decrypt(totpSecret.Secret)
+	valid := err == nil && totp.Validate(body.Code, secret)
+	if !valid {
+		valid = consumeRecoveryCode(ac.DB, userID, body.Code)
+	}
+	if !valid {
+		apierror.Respond(c, apierror.New(apierror.InvalidCredentials, "Invalid code"))
+		return
+	}
+
+	var user models.User
+	if err := ac.DB.First(&user, userID).Error; err != nil {
+		apierror.Respond(c, apierror.New(apierror.InvalidCredentials, "User not found"))
+		return
+	}
+
+	jwtToken, err := GenerateJWT(user.Email, int(user.ID))
+	if err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not generate JWT Token", err))
+		return
+	}
+	refreshToken, err := issueRefreshToken(ac.DB, user.ID, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		apierror.Respond(c, apierror.Wrap(apierror.InternalError, "Could not issue refresh token", err))
+		return
+	}
+
+	sendLoginNotificationEmail(ac.DB, user.Name, user.Email, c.ClientIP())
+
+	c.JSON(http.StatusOK, gin.H{"token": jwtToken, "refresh_token": refreshToken})
+}
+
+// consumeRecoveryCode checks code against the user's unused recovery codes,
+// marking the first match as used. Recovery codes are single-use.
+func consumeRecoveryCode(db *gorm.DB, userID uint, code string) bool {
+	var candidates []models.RecoveryCode
+	if err := db.Where("user_id = ? AND used = ?", userID, false).Find(&candidates).Error; err != nil {
+		return false
+	}
+
+	for _, candidate := range candidates {
+		if bcrypt.CompareHashAndPassword([]byte(candidate.Hash), []byte(code)) == nil {
+			candidate.Used = true
+			db.Save(&candidate)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package email
+
+import (
+	"context"
+	"os"
+
+	"github.com/mailgun/mailgun-go/v4"
+)
+
+// MailgunSender delivers mail through the Mailgun HTTP API.
+type MailgunSender struct {
+	mg       *mailgun.MailgunImpl
+	fromAddr string
+}
+
+// NewMailgunSender builds a MailgunSender configured from MAILGUN_* env vars.
+func NewMailgunSender() *MailgunSender {
+	domain := os.Getenv("MAILGUN_DOMAIN")
+	apiKey := os.Getenv("MAILGUN_API_KEY")
+	return &MailgunSender{
+		mg:       mailgun.NewMailgun(domain, apiKey),
+		fromAddr: os.Getenv("MAILGUN_FROM_EMAIL"),
+	}
+}
+
+func (s *MailgunSender) Name() string { return "mailgun" }
+
+func (s *MailgunSender) Send(ctx context.Context, msg Message) error {
+	message := s.mg.NewMessage(s.fromAddr, msg.Subject, msg.TextBody, msg.To)
+	message.SetHTML(msg.HTMLBody)
+
+	_, _, err := s.mg.Send(ctx, message)
+	return err
+}
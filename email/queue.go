@@ -0,0 +1,91 @@
+package email
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alastor-4/sylcot-go-gin-backend/models"
+	"gorm.io/gorm"
+)
+
+const (
+	queueCapacity = 256
+	workerCount   = 4
+	maxAttempts   = 5
+	baseBackoff   = 500 * time.Millisecond
+)
+
+// job is a queued delivery attempt.
+type job struct {
+	db       *gorm.DB
+	template string
+	msg      Message
+}
+
+var (
+	queueOnce sync.Once
+	queueChan chan job
+)
+
+func startWorkers() {
+	queueChan = make(chan job, queueCapacity)
+	sender, err := NewSenderFromEnv()
+	if err != nil {
+		log.Printf("email: could not initialize sender, falling back to smtp: %v", err)
+		sender = NewSMTPSender()
+	}
+
+	for i := 0; i < workerCount; i++ {
+		go worker(sender)
+	}
+}
+
+func worker(sender Sender) {
+	for j := range queueChan {
+		deliver(sender, j)
+	}
+}
+
+func deliver(sender Sender, j job) {
+	var lastErr error
+	attempts := 0
+
+	for attempts < maxAttempts {
+		attempts++
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		lastErr = sender.Send(ctx, j.msg)
+		cancel()
+		if lastErr == nil {
+			break
+		}
+		time.Sleep(baseBackoff * time.Duration(1<<uint(attempts-1)))
+	}
+
+	logEntry := models.MailLog{
+		To:       j.msg.To,
+		Template: j.template,
+		Provider: sender.Name(),
+		Success:  lastErr == nil,
+		Attempts: attempts,
+	}
+	if lastErr != nil {
+		logEntry.Error = lastErr.Error()
+		log.Printf("email: giving up delivering %q to %s after %d attempts: %v", j.template, j.msg.To, attempts, lastErr)
+	}
+
+	if j.db != nil {
+		if err := j.db.Create(&logEntry).Error; err != nil {
+			log.Printf("email: could not write mail log: %v", err)
+		}
+	}
+}
+
+// Enqueue hands a rendered message to the bounded worker pool for
+// asynchronous delivery, so callers (e.g. Register) never block on SMTP
+// latency. template identifies the template used, for audit purposes.
+func Enqueue(db *gorm.DB, template string, msg Message) {
+	queueOnce.Do(startWorkers)
+	queueChan <- job{db: db, template: template, msg: msg}
+}
@@ -0,0 +1,43 @@
+// Package email provides a pluggable, queued email transport: callers build
+// a Message, Enqueue it, and a bounded worker pool delivers it through
+// whichever Sender backend is selected via env, retrying with backoff and
+// recording the outcome to models.MailLog.
+package email
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Message is a rendered email ready to hand to a Sender backend.
+type Message struct {
+	To       string
+	Subject  string
+	HTMLBody string
+	TextBody string
+}
+
+// Sender delivers a single Message through a concrete provider.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+	Name() string
+}
+
+// NewSenderFromEnv builds the Sender selected by the EMAIL_PROVIDER env var
+// (smtp, sendgrid, mailgun, ses), defaulting to smtp.
+func NewSenderFromEnv() (Sender, error) {
+	provider := os.Getenv("EMAIL_PROVIDER")
+	switch provider {
+	case "", "smtp":
+		return NewSMTPSender(), nil
+	case "sendgrid":
+		return NewSendGridSender(), nil
+	case "mailgun":
+		return NewMailgunSender(), nil
+	case "ses":
+		return NewSESSender(), nil
+	default:
+		return nil, fmt.Errorf("email: unknown EMAIL_PROVIDER %q", provider)
+	}
+}
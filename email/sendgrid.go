@@ -0,0 +1,44 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sendgrid/sendgrid-go"
+	sgmail "github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+// SendGridSender delivers mail through the SendGrid v3 API.
+type SendGridSender struct {
+	apiKey   string
+	fromAddr string
+	fromName string
+}
+
+// NewSendGridSender builds a SendGridSender configured from SENDGRID_* env vars.
+func NewSendGridSender() *SendGridSender {
+	return &SendGridSender{
+		apiKey:   os.Getenv("SENDGRID_API_KEY"),
+		fromAddr: os.Getenv("SENDGRID_FROM_EMAIL"),
+		fromName: os.Getenv("SENDGRID_FROM_NAME"),
+	}
+}
+
+func (s *SendGridSender) Name() string { return "sendgrid" }
+
+func (s *SendGridSender) Send(ctx context.Context, msg Message) error {
+	from := sgmail.NewEmail(s.fromName, s.fromAddr)
+	to := sgmail.NewEmail("", msg.To)
+	email := sgmail.NewSingleEmail(from, msg.Subject, to, msg.TextBody, msg.HTMLBody)
+
+	client := sendgrid.NewSendClient(s.apiKey)
+	resp, err := client.SendWithContext(ctx, email)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("email: sendgrid responded with status %d: %s", resp.StatusCode, resp.Body)
+	}
+	return nil
+}
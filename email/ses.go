@@ -0,0 +1,47 @@
+package email
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// SESSender delivers mail through Amazon Simple Email Service.
+type SESSender struct {
+	fromAddr string
+}
+
+// NewSESSender builds an SESSender; AWS credentials and region are resolved
+// from the standard AWS env vars / instance profile.
+func NewSESSender() *SESSender {
+	return &SESSender{fromAddr: os.Getenv("SES_FROM_EMAIL")}
+}
+
+func (s *SESSender) Name() string { return "ses" }
+
+func (s *SESSender) Send(ctx context.Context, msg Message) error {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	client := sesv2.NewFromConfig(cfg)
+	_, err = client.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(s.fromAddr),
+		Destination:      &types.Destination{ToAddresses: []string{msg.To}},
+		Content: &types.EmailContent{
+			Simple: &types.Message{
+				Subject: &types.Content{Data: aws.String(msg.Subject)},
+				Body: &types.Body{
+					Html: &types.Content{Data: aws.String(msg.HTMLBody)},
+					Text: &types.Content{Data: aws.String(msg.TextBody)},
+				},
+			},
+		},
+	})
+	return err
+}
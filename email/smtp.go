@@ -0,0 +1,34 @@
+package email
+
+import (
+	"context"
+	"net/smtp"
+	"os"
+)
+
+// SMTPSender delivers mail through a plain SMTP relay.
+type SMTPSender struct {
+	host, port, user, password string
+}
+
+// NewSMTPSender builds an SMTPSender configured from SMTP_* env vars.
+func NewSMTPSender() *SMTPSender {
+	return &SMTPSender{
+		host:     os.Getenv("SMTP_HOST"),
+		port:     os.Getenv("SMTP_PORT"),
+		user:     os.Getenv("SMTP_USER"),
+		password: os.Getenv("SMTP_PASSWORD"),
+	}
+}
+
+func (s *SMTPSender) Name() string { return "smtp" }
+
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	auth := smtp.PlainAuth("", s.user, s.password, s.host)
+	body := "MIME-Version: 1.0\r\n" +
+		"Content-Type: text/html; charset=UTF-8\r\n" +
+		"Subject: " + msg.Subject + "\r\n\r\n" +
+		msg.HTMLBody
+
+	return smtp.SendMail(s.host+":"+s.port, auth, s.user, []string{msg.To}, []byte(body))
+}
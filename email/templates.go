@@ -0,0 +1,44 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*.html templates/*.txt
+var templateFS embed.FS
+
+// Template names for Render.
+const (
+	TemplateVerification      = "verification"
+	TemplatePasswordReset     = "password_reset"
+	TemplateLoginNotification = "login_notification"
+	TemplateAccountLocked     = "account_locked"
+)
+
+// Render produces the HTML and text bodies for the named template, filling
+// it in with data.
+func Render(name string, data interface{}) (htmlBody, textBody string, err error) {
+	htmlTpl, err := template.ParseFS(templateFS, "templates/"+name+".html")
+	if err != nil {
+		return "", "", fmt.Errorf("email: parsing html template %q: %w", name, err)
+	}
+	var htmlBuf bytes.Buffer
+	if err := htmlTpl.Execute(&htmlBuf, data); err != nil {
+		return "", "", fmt.Errorf("email: rendering html template %q: %w", name, err)
+	}
+
+	textTpl, err := texttemplate.ParseFS(templateFS, "templates/"+name+".txt")
+	if err != nil {
+		return "", "", fmt.Errorf("email: parsing text template %q: %w", name, err)
+	}
+	var textBuf bytes.Buffer
+	if err := textTpl.Execute(&textBuf, data); err != nil {
+		return "", "", fmt.Errorf("email: rendering text template %q: %w", name, err)
+	}
+
+	return htmlBuf.String(), textBuf.String(), nil
+}
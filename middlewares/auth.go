@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+)
+
+// jwtPurposeAccess must match the purpose claim controllers.GenerateJWT
+// stamps onto full access JWTs. Narrower-scoped tokens (e.g. the
+// mfa_pending token handed out between the password and TOTP steps of
+// login) use a different purpose and must never pass this check.
+const jwtPurposeAccess = "access"
+
+// AuthRequired validates the bearer JWT on protected routes and stores the
+// authenticated user's id/email in the request context.
+func AuthRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		parts := strings.SplitN(header, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or malformed Authorization header"})
+			return
+		}
+
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(parts[1], claims, func(t *jwt.Token) (interface{}, error) {
+			return []byte(os.Getenv("JWT_SECRET")), nil
+		})
+		if err != nil || !token.Valid {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+
+		if purpose, _ := claims["purpose"].(string); purpose != jwtPurposeAccess {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+
+		userID, _ := claims["userId"].(float64)
+		email, _ := claims["email"].(string)
+		c.Set("userId", uint(userID))
+		c.Set("email", email)
+		c.Next()
+	}
+}
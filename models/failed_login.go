@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FailedLogin tracks consecutive failed login attempts per email so Login
+// can apply an exponentially-growing lockout cooldown.
+type FailedLogin struct {
+	gorm.Model
+	Email       string     `json:"-" gorm:"uniqueIndex"`
+	Attempts    int        `json:"-"`
+	LockedUntil *time.Time `json:"-"`
+}
+
+// IsLocked reports whether the account is currently under a lockout cooldown.
+func (f *FailedLogin) IsLocked() bool {
+	return f.LockedUntil != nil && time.Now().Before(*f.LockedUntil)
+}
@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// MailLog records the outcome of an attempted email delivery for audit and
+// support purposes.
+type MailLog struct {
+	gorm.Model
+	To       string `json:"to"`
+	Template string `json:"template"`
+	Provider string `json:"provider"`
+	Success  bool   `json:"success"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// OAuthIdentity links a local User to an identity at an external OAuth2/OIDC
+// provider, so a single account can be reached through several providers.
+type OAuthIdentity struct {
+	gorm.Model
+	UserID       uint   `json:"-" gorm:"index:idx_oauth_user"`
+	Provider     string `json:"provider" gorm:"index:idx_oauth_provider_subject,unique"`
+	Subject      string `json:"-" gorm:"index:idx_oauth_provider_subject,unique"`
+	Email        string `json:"email"`
+	AccessToken  string `json:"-"`
+	RefreshToken string `json:"-"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetToken is a single-use, SHA-256-hashed token that authorizes
+// one password reset. Only the hash is ever persisted.
+type PasswordResetToken struct {
+	gorm.Model
+	UserID    uint       `json:"-" gorm:"index"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex"`
+	ExpiresAt time.Time  `json:"-"`
+	UsedAt    *time.Time `json:"-"`
+}
+
+// IsActive reports whether the token can still be redeemed.
+func (t *PasswordResetToken) IsActive() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
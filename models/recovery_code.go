@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// RecoveryCode is a bcrypt-hashed single-use code that lets a user bypass
+// TOTP if they lose access to their authenticator.
+type RecoveryCode struct {
+	gorm.Model
+	UserID uint   `json:"-" gorm:"index"`
+	Hash   string `json:"-"`
+	Used   bool   `json:"-" gorm:"default:false"`
+}
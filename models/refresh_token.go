@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshToken is an opaque, long-lived token exchanged for a new access
+// JWT. Only its SHA-256 hash is ever persisted; rotation chains tokens via
+// ReplacedBy so a reused, already-revoked token reveals theft.
+type RefreshToken struct {
+	gorm.Model
+	UserID     uint       `json:"-" gorm:"index"`
+	JTI        string     `json:"-" gorm:"uniqueIndex"`
+	TokenHash  string     `json:"-" gorm:"uniqueIndex"`
+	ExpiresAt  time.Time  `json:"-"`
+	RevokedAt  *time.Time `json:"-"`
+	ReplacedBy string     `json:"-"`
+	UserAgent  string     `json:"-"`
+	IP         string     `json:"-"`
+}
+
+// IsActive reports whether the token can still be redeemed.
+func (rt *RefreshToken) IsActive() bool {
+	return rt.RevokedAt == nil && time.Now().Before(rt.ExpiresAt)
+}
@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// TOTPSecret stores a user's TOTP enrollment. Secret is encrypted at rest;
+// Confirmed is false until the user proves possession with a valid code.
+type TOTPSecret struct {
+	gorm.Model
+	UserID    uint   `json:"-" gorm:"uniqueIndex"`
+	Secret    string `json:"-"`
+	Confirmed bool   `json:"-" gorm:"default:false"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// User represents a registered account.
+type User struct {
+	gorm.Model
+	Name       string `json:"name" validate:"required,min=2,max=100"`
+	Email      string `json:"email" gorm:"uniqueIndex" validate:"required,email"`
+	Password   string `json:"password" validate:"required,min=8"`
+	IsVerified bool   `json:"-" gorm:"default:false"`
+	Token      string `json:"-" gorm:"index"`
+}
+
+// Validate runs struct-tag validation over the user's fields.
+func (u *User) Validate() error {
+	return validate.Struct(u)
+}
+
+// GetValidationMessages turns a validator.ValidationErrors into a
+// field-name -> human message map suitable for API responses.
+func GetValidationMessages(err error) map[string]string {
+	messages := make(map[string]string)
+
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		messages["error"] = err.Error()
+		return messages
+	}
+
+	for _, fieldErr := range validationErrors {
+		field := fieldErr.Field()
+		switch fieldErr.Tag() {
+		case "required":
+			messages[field] = field + " is required"
+		case "email":
+			messages[field] = "must be a valid email address"
+		case "min":
+			messages[field] = field + " is too short"
+		case "max":
+			messages[field] = field + " is too long"
+		default:
+			messages[field] = field + " is invalid"
+		}
+	}
+
+	return messages
+}
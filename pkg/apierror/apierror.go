@@ -0,0 +1,84 @@
+// Package apierror defines a typed API error so clients can branch on a
+// stable code instead of matching on human-readable message strings.
+package apierror
+
+import "net/http"
+
+// ErrorCode identifies a specific failure condition, stable across releases.
+type ErrorCode string
+
+const (
+	InvalidRequest     ErrorCode = "INVALID_REQUEST"
+	ValidationFailed   ErrorCode = "VALIDATION_FAILED"
+	InvalidEmail       ErrorCode = "INVALID_EMAIL"
+	InvalidPassword    ErrorCode = "INVALID_PASSWORD"
+	UserAlreadyExists  ErrorCode = "USER_ALREADY_EXISTS"
+	InvalidCredentials ErrorCode = "INVALID_CREDENTIALS"
+	EmailNotVerified   ErrorCode = "EMAIL_NOT_VERIFIED"
+	TokenExpired       ErrorCode = "TOKEN_EXPIRED"
+	TokenInvalid       ErrorCode = "TOKEN_INVALID"
+	TooManyAttempts    ErrorCode = "TOO_MANY_ATTEMPTS"
+	InternalError      ErrorCode = "INTERNAL_ERROR"
+)
+
+var statusByCode = map[ErrorCode]int{
+	InvalidRequest:     http.StatusBadRequest,
+	ValidationFailed:   http.StatusBadRequest,
+	InvalidEmail:       http.StatusBadRequest,
+	InvalidPassword:    http.StatusBadRequest,
+	UserAlreadyExists:  http.StatusConflict,
+	InvalidCredentials: http.StatusUnauthorized,
+	EmailNotVerified:   http.StatusForbidden,
+	TokenExpired:       http.StatusUnauthorized,
+	TokenInvalid:       http.StatusUnauthorized,
+	TooManyAttempts:    http.StatusTooManyRequests,
+	InternalError:      http.StatusInternalServerError,
+}
+
+// Error is a typed, user-presentable API error that still carries the
+// underlying cause for logging.
+type Error struct {
+	Code        ErrorCode   `json:"code"`
+	UserMessage string      `json:"message"`
+	Details     interface{} `json:"details,omitempty"`
+	err         error
+}
+
+func (e *Error) Error() string {
+	if e.err != nil {
+		return e.UserMessage + ": " + e.err.Error()
+	}
+	return e.UserMessage
+}
+
+// Unwrap exposes the wrapped cause for errors.Is/errors.As.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Status returns the HTTP status code associated with the error's code,
+// defaulting to 500 for unrecognized codes.
+func (e *Error) Status() int {
+	if status, ok := statusByCode[e.Code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// New builds an Error with no wrapped cause.
+func New(code ErrorCode, userMessage string) *Error {
+	return &Error{Code: code, UserMessage: userMessage}
+}
+
+// Wrap builds an Error that carries the original cause for logging, while
+// keeping the response message safe to show to clients.
+func Wrap(code ErrorCode, userMessage string, err error) *Error {
+	return &Error{Code: code, UserMessage: userMessage, err: err}
+}
+
+// WithDetails attaches structured details (e.g. field validation messages)
+// to the error and returns it for chaining.
+func (e *Error) WithDetails(details interface{}) *Error {
+	e.Details = details
+	return e
+}
@@ -0,0 +1,9 @@
+package apierror
+
+import "github.com/gin-gonic/gin"
+
+// Respond serializes err as {code, message, details} with the HTTP status
+// matching its code, and aborts the context so later handlers don't run.
+func Respond(c *gin.Context, err *Error) {
+	c.AbortWithStatusJSON(err.Status(), err)
+}
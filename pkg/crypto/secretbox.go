@@ -0,0 +1,87 @@
+// Package crypto provides a thin AES-GCM helper for encrypting small
+// secrets (e.g. TOTP seeds) at rest.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+)
+
+func key() ([]byte, error) {
+	encoded := os.Getenv("SECRET_ENCRYPTION_KEY")
+	if encoded == "" {
+		return nil, errors.New("crypto: SECRET_ENCRYPTION_KEY is not set")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("crypto: SECRET_ENCRYPTION_KEY must be base64-encoded")
+	}
+	if len(key) != 32 {
+		return nil, errors.New("crypto: SECRET_ENCRYPTION_KEY must decode to 32 bytes")
+	}
+	return key, nil
+}
+
+// Encrypt returns a base64-encoded, AES-256-GCM sealed version of plaintext.
+func Encrypt(plaintext string) (string, error) {
+	k, err := key()
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt.
+func Decrypt(encoded string) (string, error) {
+	k, err := key()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("crypto: ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:nonceSize], sealed[nonceSize:]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}